@@ -0,0 +1,429 @@
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/poseidon2"
+
+	"github.com/heliaxdev/poseidon2-evm-bls12-381/evm"
+	"github.com/heliaxdev/poseidon2-evm-bls12-381/hash"
+	"github.com/heliaxdev/poseidon2-evm-bls12-381/merkle"
+)
+
+// runCompress implements `compress left right`: the 2-to-1 Poseidon2
+// compression function. With --batch it instead reads newline-delimited
+// "left right" pairs from stdin and streams one result per line.
+func runCompress(args []string) error {
+	fs := flag.NewFlagSet("compress", flag.ExitOnError)
+	format := fs.String("format", "decimal", "output format: decimal|hex|json")
+	batch := fs.Bool("batch", false, `read newline-delimited "left right" pairs from stdin`)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *batch {
+		return batchLines(func(fields []string) (string, error) {
+			if len(fields) != 2 {
+				return "", fmt.Errorf("expected 2 fields per line, got %d", len(fields))
+			}
+			left, err := parseElement(fields[0])
+			if err != nil {
+				return "", err
+			}
+			right, err := parseElement(fields[1])
+			if err != nil {
+				return "", err
+			}
+			return formatElement(merkle.Compress(left, right), *format)
+		})
+	}
+
+	rest := fs.Args()
+	if len(rest) != 2 {
+		return fmt.Errorf("compress: expected 2 arguments (left, right), got %d", len(rest))
+	}
+	left, err := readElementArg(rest[0], os.Stdin)
+	if err != nil {
+		return err
+	}
+	right, err := readElementArg(rest[1], os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	out, err := formatElement(merkle.Compress(left, right), *format)
+	if err != nil {
+		return err
+	}
+	fmt.Println(out)
+	return nil
+}
+
+// runHash implements `hash [elements...]`: sponge-absorbs every argument as
+// a field element and prints the squeezed output.
+func runHash(args []string) error {
+	fs := flag.NewFlagSet("hash", flag.ExitOnError)
+	format := fs.String("format", "decimal", "output format: decimal|hex|json")
+	rate := fs.Int("rate", 1, "sponge rate (field elements per block)")
+	capacity := fs.Int("capacity", 1, "sponge capacity (field elements)")
+	domain := fs.String("domain", "", "0x-hex domain separation tag")
+	batch := fs.Bool("batch", false, "treat each stdin line as one hash input (space-separated elements)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	domainBytes, err := parseDomain(*domain)
+	if err != nil {
+		return err
+	}
+
+	if *batch {
+		return batchLines(func(fields []string) (string, error) {
+			h, err := hash.New(*rate, *capacity, domainBytes)
+			if err != nil {
+				return "", err
+			}
+			for _, f := range fields {
+				e, err := parseElement(f)
+				if err != nil {
+					return "", err
+				}
+				if err := h.WriteElement(e); err != nil {
+					return "", err
+				}
+			}
+			return formatElement(h.Sum(), *format)
+		})
+	}
+
+	h, err := hash.New(*rate, *capacity, domainBytes)
+	if err != nil {
+		return err
+	}
+	for _, a := range fs.Args() {
+		e, err := readElementArg(a, os.Stdin)
+		if err != nil {
+			return err
+		}
+		if err := h.WriteElement(e); err != nil {
+			return err
+		}
+	}
+
+	out, err := formatElement(h.Sum(), *format)
+	if err != nil {
+		return err
+	}
+	fmt.Println(out)
+	return nil
+}
+
+// runPermute implements `permute elements...`: the raw Poseidon2
+// permutation over a state of the given width.
+func runPermute(args []string) error {
+	fs := flag.NewFlagSet("permute", flag.ExitOnError)
+	format := fs.String("format", "decimal", "output format: decimal|hex|json")
+	width := fs.Int("width", 2, "state width")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *width != 2 && *width != 3 {
+		return fmt.Errorf("permute: unsupported width %d; only 2 or 3 are supported", *width)
+	}
+
+	rest := fs.Args()
+	if len(rest) != *width {
+		return fmt.Errorf("permute: expected %d arguments for width %d, got %d", *width, *width, len(rest))
+	}
+
+	state := make([]fr.Element, *width)
+	for i, a := range rest {
+		e, err := readElementArg(a, os.Stdin)
+		if err != nil {
+			return err
+		}
+		state[i] = e
+	}
+
+	perm := poseidon2.NewPermutation(*width, 8, 56)
+	perm.Permutation(state)
+
+	results := make([]string, *width)
+	for i, e := range state {
+		out, err := formatElement(e, *format)
+		if err != nil {
+			return err
+		}
+		results[i] = out
+	}
+	fmt.Println(strings.Join(results, " "))
+	return nil
+}
+
+// buildMerkleTree inserts leaves 0..len(leaves)-1 into a fresh tree of the
+// given depth.
+func buildMerkleTree(depth int, leaves []fr.Element) (*merkle.Tree, error) {
+	t, err := merkle.NewTree(depth)
+	if err != nil {
+		return nil, err
+	}
+	if len(leaves) > 1<<uint(depth) {
+		return nil, fmt.Errorf("merkle: %d leaves do not fit in depth %d (max %d)", len(leaves), depth, 1<<uint(depth))
+	}
+	for i, leaf := range leaves {
+		t.Insert(uint64(i), leaf)
+	}
+	return t, nil
+}
+
+func runMerkleRoot(args []string) error {
+	fs := flag.NewFlagSet("merkle-root", flag.ExitOnError)
+	format := fs.String("format", "decimal", "output format: decimal|hex|json")
+	depth := fs.Int("depth", 0, "tree depth (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	depthGiven := false
+	fs.Visit(func(f *flag.Flag) { depthGiven = depthGiven || f.Name == "depth" })
+	if !depthGiven {
+		return fmt.Errorf("merkle-root: --depth is required")
+	}
+
+	leaves, err := parseElements(fs.Args(), os.Stdin)
+	if err != nil {
+		return err
+	}
+	t, err := buildMerkleTree(*depth, leaves)
+	if err != nil {
+		return err
+	}
+
+	out, err := formatElement(t.Root(), *format)
+	if err != nil {
+		return err
+	}
+	fmt.Println(out)
+	return nil
+}
+
+func runMerkleProve(args []string) error {
+	fs := flag.NewFlagSet("merkle-prove", flag.ExitOnError)
+	format := fs.String("format", "decimal", "output format for each path element: decimal|hex|json")
+	depth := fs.Int("depth", 0, "tree depth (required)")
+	index := fs.Uint64("index", 0, "index of the leaf to prove")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	depthGiven := false
+	fs.Visit(func(f *flag.Flag) { depthGiven = depthGiven || f.Name == "depth" })
+	if !depthGiven {
+		return fmt.Errorf("merkle-prove: --depth is required")
+	}
+
+	leaves, err := parseElements(fs.Args(), os.Stdin)
+	if err != nil {
+		return err
+	}
+	t, err := buildMerkleTree(*depth, leaves)
+	if err != nil {
+		return err
+	}
+
+	path, indices, err := t.Prove(*index)
+	if err != nil {
+		return err
+	}
+
+	if *format == "json" {
+		pathHex := make([]string, len(path))
+		for i, e := range path {
+			pathHex[i] = elementHex(e)
+		}
+		b, err := json.Marshal(map[string]any{"path": pathHex, "indices": indices})
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+	}
+
+	pathStrs := make([]string, len(path))
+	for i, e := range path {
+		out, err := formatElement(e, *format)
+		if err != nil {
+			return err
+		}
+		pathStrs[i] = out
+	}
+	indexStrs := make([]string, len(indices))
+	for i, b := range indices {
+		indexStrs[i] = strconv.FormatBool(b)
+	}
+	fmt.Printf("path: %s\n", strings.Join(pathStrs, ","))
+	fmt.Printf("indices: %s\n", strings.Join(indexStrs, ","))
+	return nil
+}
+
+func runMerkleVerify(args []string) error {
+	fs := flag.NewFlagSet("merkle-verify", flag.ExitOnError)
+	root := fs.String("root", "", "expected root (required)")
+	leaf := fs.String("leaf", "", "leaf value (required)")
+	path := fs.String("path", "", "comma-separated sibling path")
+	indices := fs.String("indices", "", "comma-separated true/false, one per path element")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	rootElem, err := readElementArg(*root, os.Stdin)
+	if err != nil {
+		return fmt.Errorf("--root: %w", err)
+	}
+	leafElem, err := readElementArg(*leaf, os.Stdin)
+	if err != nil {
+		return fmt.Errorf("--leaf: %w", err)
+	}
+	pathElems, err := parseElements(splitNonEmpty(*path, ","), os.Stdin)
+	if err != nil {
+		return fmt.Errorf("--path: %w", err)
+	}
+	indexBools, err := parseBools(splitNonEmpty(*indices, ","))
+	if err != nil {
+		return fmt.Errorf("--indices: %w", err)
+	}
+	if len(pathElems) != len(indexBools) {
+		return fmt.Errorf("--path has %d elements but --indices has %d", len(pathElems), len(indexBools))
+	}
+
+	ok := merkle.Verify(rootElem, leafElem, pathElems, indexBools)
+	fmt.Println(ok)
+	if !ok {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// parseElements parses each string in args as a field element, resolving
+// "-" to raw little-endian bytes read from r (see readElementArg).
+func parseElements(args []string, r io.Reader) ([]fr.Element, error) {
+	out := make([]fr.Element, len(args))
+	for i, a := range args {
+		e, err := readElementArg(a, r)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = e
+	}
+	return out, nil
+}
+
+func parseBools(args []string) ([]bool, error) {
+	out := make([]bool, len(args))
+	for i, a := range args {
+		b, err := strconv.ParseBool(a)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bool %q: %w", a, err)
+		}
+		out[i] = b
+	}
+	return out, nil
+}
+
+func splitNonEmpty(s, sep string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, sep)
+}
+
+func parseDomain(s string) ([]byte, error) {
+	if s == "" {
+		return nil, nil
+	}
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("--domain: %w", err)
+	}
+	return b, nil
+}
+
+// batchLines reads newline-delimited, whitespace-separated records from
+// stdin and streams the result of fn for each one to stdout, so large
+// inputs don't need to be buffered in memory or re-run one process per
+// line.
+func batchLines(fn func(fields []string) (string, error)) error {
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		out, err := fn(fields)
+		if err != nil {
+			return fmt.Errorf("batch line %q: %w", line, err)
+		}
+		if _, err := fmt.Fprintln(w, out); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// runEvmVectors implements `evm-vectors`: it reads newline-delimited
+// "left right" pairs from stdin, same as `compress --batch`, and writes a
+// Foundry/Hardhat-loadable compress_vectors.json into --out.
+func runEvmVectors(args []string) error {
+	fs := flag.NewFlagSet("evm-vectors", flag.ExitOnError)
+	out := fs.String("out", ".", "directory to write compress_vectors.json into")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var pairs [][2]fr.Element
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return fmt.Errorf("evm-vectors: expected 2 fields per line, got %d in %q", len(fields), line)
+		}
+		left, err := parseElement(fields[0])
+		if err != nil {
+			return err
+		}
+		right, err := parseElement(fields[1])
+		if err != nil {
+			return err
+		}
+		pairs = append(pairs, [2]fr.Element{left, right})
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	path, err := evm.GenerateVectorFile(*out, pairs)
+	if err != nil {
+		return err
+	}
+	fmt.Println(path)
+	return nil
+}
@@ -0,0 +1,109 @@
+// Package evm is the Solidity-facing reference encoder for this module: it
+// produces the exact 32-byte big-endian encoding and ABI calldata a Solidity
+// Poseidon2 verifier would expect, plus JSON test vectors that a
+// Foundry/Hardhat suite can load. This package, not the Solidity contract,
+// is the canonical oracle for "poseidon2-evm-bls12-381" — the Go
+// compression function is considered correct by definition.
+package evm
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/heliaxdev/poseidon2-evm-bls12-381/merkle"
+)
+
+// CompressSignature is the Solidity function signature used to derive the
+// 4-byte selector for CompressCalldata.
+const CompressSignature = "compress(uint256,uint256)"
+
+// Compress runs the same 2-to-1 Poseidon2 compression as the merkle and CLI
+// packages and returns it as the 32-byte big-endian encoding Solidity's
+// uint256 expects.
+func Compress(left, right fr.Element) [32]byte {
+	result := merkle.Compress(left, right)
+	return result.Bytes()
+}
+
+// Selector returns the first 4 bytes of keccak256(signature), matching
+// Solidity's function selector derivation.
+func Selector(signature string) [4]byte {
+	digest := sha3.NewLegacyKeccak256()
+	digest.Write([]byte(signature))
+	sum := digest.Sum(nil)
+
+	var out [4]byte
+	copy(out[:], sum[:4])
+	return out
+}
+
+// CompressCalldata returns the calldata a Solidity test harness would pass
+// to a `compress(uint256,uint256)` verifier: the 4-byte selector followed
+// by left and right, each left-padded to 32 bytes.
+func CompressCalldata(left, right fr.Element) []byte {
+	sel := Selector(CompressSignature)
+	leftBytes := left.Bytes()
+	rightBytes := right.Bytes()
+
+	data := make([]byte, 0, len(sel)+len(leftBytes)+len(rightBytes))
+	data = append(data, sel[:]...)
+	data = append(data, leftBytes[:]...)
+	data = append(data, rightBytes[:]...)
+	return data
+}
+
+// Vector is one {inputs, expected} row of a Foundry/Hardhat-loadable test
+// vector file.
+type Vector struct {
+	Inputs   []string `json:"inputs"`
+	Expected string   `json:"expected"`
+}
+
+// CompressVector builds the test vector for one compress(left, right) call.
+func CompressVector(left, right fr.Element) Vector {
+	result := Compress(left, right)
+	return Vector{
+		Inputs:   []string{hexElement(left), hexElement(right)},
+		Expected: "0x" + hex.EncodeToString(result[:]),
+	}
+}
+
+func hexElement(e fr.Element) string {
+	b := e.Bytes()
+	return "0x" + hex.EncodeToString(b[:])
+}
+
+// WriteVectorFile marshals vectors as indented JSON and writes them to
+// path, creating or truncating the file.
+func WriteVectorFile(path string, vectors []Vector) error {
+	data, err := json.MarshalIndent(vectors, "", "  ")
+	if err != nil {
+		return fmt.Errorf("evm: marshaling vectors: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("evm: writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// GenerateVectorFile builds a compress vector for every (left, right) pair
+// and writes them to "compress_vectors.json" inside dir, returning the
+// file's path.
+func GenerateVectorFile(dir string, pairs [][2]fr.Element) (string, error) {
+	vectors := make([]Vector, len(pairs))
+	for i, p := range pairs {
+		vectors[i] = CompressVector(p[0], p[1])
+	}
+
+	path := filepath.Join(dir, "compress_vectors.json")
+	if err := WriteVectorFile(path, vectors); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,84 @@
+package evm
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+
+	"github.com/heliaxdev/poseidon2-evm-bls12-381/merkle"
+)
+
+func TestCompressMatchesMerkleCompress(t *testing.T) {
+	var left, right fr.Element
+	left.SetUint64(1)
+	right.SetUint64(2)
+
+	want := merkle.Compress(left, right)
+	wantBytes := want.Bytes()
+
+	got := Compress(left, right)
+	if got != wantBytes {
+		t.Fatalf("Compress mismatch: got %x, want %x", got, wantBytes)
+	}
+}
+
+func TestCompressCalldataLayout(t *testing.T) {
+	var left, right fr.Element
+	left.SetUint64(1)
+	right.SetUint64(2)
+
+	data := CompressCalldata(left, right)
+	if len(data) != 4+32+32 {
+		t.Fatalf("calldata length = %d, want %d", len(data), 4+32+32)
+	}
+
+	sel := Selector(CompressSignature)
+	if hex.EncodeToString(data[:4]) != hex.EncodeToString(sel[:]) {
+		t.Fatalf("calldata selector = %x, want %x", data[:4], sel)
+	}
+
+	leftBytes := left.Bytes()
+	if hex.EncodeToString(data[4:36]) != hex.EncodeToString(leftBytes[:]) {
+		t.Fatalf("calldata left argument = %x, want %x", data[4:36], leftBytes)
+	}
+}
+
+func TestGenerateVectorFileRoundTrips(t *testing.T) {
+	var a, b, c, d fr.Element
+	a.SetUint64(1)
+	b.SetUint64(2)
+	c.SetUint64(3)
+	d.SetUint64(4)
+
+	dir := t.TempDir()
+	path, err := GenerateVectorFile(dir, [][2]fr.Element{{a, b}, {c, d}})
+	if err != nil {
+		t.Fatalf("GenerateVectorFile: %v", err)
+	}
+	if path != filepath.Join(dir, "compress_vectors.json") {
+		t.Fatalf("unexpected path: %s", path)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading vector file: %v", err)
+	}
+
+	var vectors []Vector
+	if err := json.Unmarshal(raw, &vectors); err != nil {
+		t.Fatalf("unmarshaling vector file: %v", err)
+	}
+	if len(vectors) != 2 {
+		t.Fatalf("got %d vectors, want 2", len(vectors))
+	}
+
+	want := CompressVector(a, b)
+	if vectors[0].Expected != want.Expected || len(vectors[0].Inputs) != len(want.Inputs) ||
+		vectors[0].Inputs[0] != want.Inputs[0] || vectors[0].Inputs[1] != want.Inputs[1] {
+		t.Fatalf("vector[0] = %+v, want %+v", vectors[0], want)
+	}
+}
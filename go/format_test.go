@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+func TestParseElementDecimalAndHex(t *testing.T) {
+	dec, err := parseElement("123")
+	if err != nil {
+		t.Fatalf("parseElement(123): %v", err)
+	}
+	hex, err := parseElement("0x7b")
+	if err != nil {
+		t.Fatalf("parseElement(0x7b): %v", err)
+	}
+	if !dec.Equal(&hex) {
+		t.Fatalf("decimal and hex encodings of 123 disagree: %s vs %s", dec.String(), hex.String())
+	}
+}
+
+func TestParseElementRejectsGarbage(t *testing.T) {
+	if _, err := parseElement("not-a-number"); err == nil {
+		t.Fatal("expected an error for a non-numeric element, got nil")
+	}
+	if _, err := parseElement("0xzz"); err == nil {
+		t.Fatal("expected an error for invalid hex, got nil")
+	}
+}
+
+func TestFormatElementDecimalHexJSON(t *testing.T) {
+	var e fr.Element
+	e.SetUint64(255)
+
+	dec, err := formatElement(e, "decimal")
+	if err != nil {
+		t.Fatalf("formatElement decimal: %v", err)
+	}
+	if dec != "255" {
+		t.Fatalf("decimal = %q, want %q", dec, "255")
+	}
+
+	hex, err := formatElement(e, "hex")
+	if err != nil {
+		t.Fatalf("formatElement hex: %v", err)
+	}
+	if !strings.HasPrefix(hex, "0x") || !strings.HasSuffix(hex, "ff") {
+		t.Fatalf("hex = %q, want 0x-prefixed and ending in ff", hex)
+	}
+
+	js, err := formatElement(e, "json")
+	if err != nil {
+		t.Fatalf("formatElement json: %v", err)
+	}
+	if !strings.Contains(js, `"result"`) || !strings.Contains(js, hex) {
+		t.Fatalf("json = %q, want it to contain the hex result %q", js, hex)
+	}
+
+	if _, err := formatElement(e, "bogus"); err == nil {
+		t.Fatal("expected an error for an unknown format, got nil")
+	}
+}
+
+func TestReadElementArgDash(t *testing.T) {
+	// little-endian [1, 0, 0, ...] decodes to the field element 1.
+	buf := make([]byte, 32)
+	buf[0] = 1
+	e, err := readElementArg("-", bytes.NewReader(buf))
+	if err != nil {
+		t.Fatalf("readElementArg(-): %v", err)
+	}
+	var want fr.Element
+	want.SetUint64(1)
+	if !e.Equal(&want) {
+		t.Fatalf("readElementArg(-) = %s, want %s", e.String(), want.String())
+	}
+}
+
+func TestReadElementArgNonDash(t *testing.T) {
+	e, err := readElementArg("42", bytes.NewReader(nil))
+	if err != nil {
+		t.Fatalf("readElementArg(42): %v", err)
+	}
+	var want fr.Element
+	want.SetUint64(42)
+	if !e.Equal(&want) {
+		t.Fatalf("readElementArg(42) = %s, want %s", e.String(), want.String())
+	}
+}
+
+func TestParseDomain(t *testing.T) {
+	b, err := parseDomain("0xabcd")
+	if err != nil {
+		t.Fatalf("parseDomain: %v", err)
+	}
+	if len(b) != 2 || b[0] != 0xab || b[1] != 0xcd {
+		t.Fatalf("parseDomain(0xabcd) = %x, want abcd", b)
+	}
+
+	if b, err := parseDomain(""); err != nil || b != nil {
+		t.Fatalf("parseDomain(\"\") = (%x, %v), want (nil, nil)", b, err)
+	}
+}
+
+func TestSplitNonEmpty(t *testing.T) {
+	if got := splitNonEmpty("", ","); got != nil {
+		t.Fatalf("splitNonEmpty(\"\") = %v, want nil", got)
+	}
+	if got := splitNonEmpty("a,b,c", ","); len(got) != 3 {
+		t.Fatalf("splitNonEmpty(a,b,c) = %v, want 3 elements", got)
+	}
+}
+
+func TestParseBools(t *testing.T) {
+	got, err := parseBools([]string{"true", "false"})
+	if err != nil {
+		t.Fatalf("parseBools: %v", err)
+	}
+	if len(got) != 2 || got[0] != true || got[1] != false {
+		t.Fatalf("parseBools = %v, want [true false]", got)
+	}
+
+	if _, err := parseBools([]string{"nope"}); err == nil {
+		t.Fatal("expected an error for an invalid bool, got nil")
+	}
+}
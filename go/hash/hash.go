@@ -0,0 +1,152 @@
+// Package hash provides a sponge-construction hasher built on top of the
+// Poseidon2 permutation, so variable-length byte strings or field elements
+// can be absorbed and squeezed down to a single canonical fr.Element. The
+// API mirrors the shape of Go's hash.Hash (New/Write/Sum/Reset) but returns
+// an fr.Element from Sum rather than appending raw bytes, since the result
+// is meant to be consumed as a SNARK-field value.
+package hash
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/poseidon2"
+)
+
+// elementBytes is the number of bytes packed per absorbed field element.
+// 31 bytes keeps every packed value strictly below the bls12-381 scalar
+// field modulus, so SetBytes never wraps.
+const elementBytes = 31
+
+// Hasher absorbs bytes or field elements into a sponge of width
+// rate+capacity and squeezes a single fr.Element out of state[0].
+type Hasher struct {
+	rate     int
+	capacity int
+	perm     *poseidon2.Permutation
+	domain   []byte
+
+	state    []fr.Element
+	pos      int // rate-slots absorbed since the last permutation
+	buf      []byte
+	totalLen int64
+	done     bool
+}
+
+// New creates a sponge of width rate+capacity over Poseidon2(rate+capacity,
+// 8, 56). domain is mixed into the capacity portion at construction time so
+// callers that need domain separation (e.g. a t=3 variant hashing three
+// leaves at once vs. the t=2 compression) never collide on output. rate+
+// capacity must be 2 or 3, the only widths poseidon2.NewPermutation
+// supports; any other value is rejected here instead of panicking deep
+// inside the permutation.
+func New(rate, capacity int, domain []byte) (*Hasher, error) {
+	width := rate + capacity
+	if width != 2 && width != 3 {
+		return nil, fmt.Errorf("hash: unsupported sponge width %d (rate+capacity); only 2 or 3 are supported", width)
+	}
+
+	h := &Hasher{
+		rate:     rate,
+		capacity: capacity,
+		perm:     poseidon2.NewPermutation(width, 8, 56),
+		domain:   domain,
+	}
+	h.Reset()
+	return h, nil
+}
+
+// Reset restores the sponge to its initial state, ready to absorb a new
+// input under the same domain tag.
+func (h *Hasher) Reset() {
+	h.state = make([]fr.Element, h.rate+h.capacity)
+	h.pos = 0
+	h.buf = nil
+	h.totalLen = 0
+	h.done = false
+
+	if len(h.domain) > 0 {
+		var tag fr.Element
+		tag.SetBytes(h.domain)
+		h.state[h.rate].Add(&h.state[h.rate], &tag)
+	}
+}
+
+// absorb adds e into the next free rate slot, permuting first if the rate
+// portion of the state is already full.
+func (h *Hasher) absorb(e fr.Element) {
+	if h.pos == h.rate {
+		h.perm.Permutation(h.state)
+		h.pos = 0
+	}
+	h.state[h.pos].Add(&h.state[h.pos], &e)
+	h.pos++
+}
+
+// WriteElement absorbs a field element directly, without going through the
+// byte-packing path. Like Write, it returns an error if called after Sum;
+// call Reset first to start a new hash.
+func (h *Hasher) WriteElement(e fr.Element) error {
+	if h.done {
+		return errors.New("hash: WriteElement called after Sum; call Reset first")
+	}
+	h.absorb(e)
+	return nil
+}
+
+// Write implements io.Writer, packing bytes into field elements of
+// elementBytes each as they arrive. It returns an error once Sum has been
+// called; call Reset to start a new hash.
+func (h *Hasher) Write(p []byte) (int, error) {
+	if h.done {
+		return 0, errors.New("hash: Write called after Sum; call Reset first")
+	}
+
+	n := len(p)
+	h.totalLen += int64(n)
+	h.buf = append(h.buf, p...)
+
+	for len(h.buf) >= elementBytes {
+		var e fr.Element
+		e.SetBytes(h.buf[:elementBytes])
+		h.absorb(e)
+		h.buf = h.buf[elementBytes:]
+	}
+
+	return n, nil
+}
+
+// Sum applies 10* padding to any buffered bytes, mixes the total input
+// length into the capacity, runs the final permutation over the padded
+// block, and returns the squeezed element. It is safe to call Sum more
+// than once; only the first call pads, permutes, and finalizes.
+func (h *Hasher) Sum() fr.Element {
+	if !h.done {
+		h.pad()
+		h.perm.Permutation(h.state)
+		h.done = true
+	}
+	return h.state[0]
+}
+
+// pad appends a 10* padding block (a 0x01 byte followed by zeros up to the
+// next element boundary) and mixes the total absorbed length into the
+// capacity, so messages that differ only in trailing zero bytes still
+// produce distinct digests.
+func (h *Hasher) pad() {
+	padded := append(append([]byte{}, h.buf...), 0x01)
+	for len(padded)%elementBytes != 0 {
+		padded = append(padded, 0x00)
+	}
+
+	for i := 0; i < len(padded); i += elementBytes {
+		var e fr.Element
+		e.SetBytes(padded[i : i+elementBytes])
+		h.absorb(e)
+	}
+
+	var lenTag fr.Element
+	lenTag.SetUint64(uint64(h.totalLen))
+	h.state[h.rate].Add(&h.state[h.rate], &lenTag)
+}
@@ -0,0 +1,163 @@
+package hash
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+func TestNewRejectsUnsupportedWidth(t *testing.T) {
+	for _, width := range []struct{ rate, capacity int }{
+		{0, 1}, {1, 0}, {2, 2}, {4, 1},
+	} {
+		if _, err := New(width.rate, width.capacity, nil); err == nil {
+			t.Fatalf("New(%d, %d, nil): expected an error, got nil", width.rate, width.capacity)
+		}
+	}
+}
+
+func TestNewAcceptsSupportedWidths(t *testing.T) {
+	for _, width := range []struct{ rate, capacity int }{
+		{1, 1}, {1, 2}, {2, 1},
+	} {
+		if _, err := New(width.rate, width.capacity, nil); err != nil {
+			t.Fatalf("New(%d, %d, nil): unexpected error: %v", width.rate, width.capacity, err)
+		}
+	}
+}
+
+func TestSumIsDeterministicAndMixesTrailingBlock(t *testing.T) {
+	var a, b fr.Element
+	a.SetUint64(1)
+	b.SetUint64(2)
+
+	h1, err := New(1, 1, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	h1.WriteElement(a)
+	sum1 := h1.Sum()
+
+	h2, err := New(1, 1, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	h2.WriteElement(b)
+	sum2 := h2.Sum()
+
+	if sum1.Equal(&sum2) {
+		t.Fatal("hashing different single elements produced the same digest")
+	}
+
+	// Calling Sum twice must be idempotent.
+	again := h1.Sum()
+	if !again.Equal(&sum1) {
+		t.Fatalf("second Sum() call changed the result: got %s, want %s", again.String(), sum1.String())
+	}
+}
+
+func TestWriteChunkingIsStreamInvariant(t *testing.T) {
+	payload := make([]byte, elementBytes*2+5)
+	for i := range payload {
+		payload[i] = byte(i + 1)
+	}
+
+	h1, err := New(1, 1, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := h1.Write(payload); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	whole := h1.Sum()
+
+	h2, err := New(1, 1, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	for _, chunk := range [][]byte{payload[:7], payload[7:20], payload[20:]} {
+		if _, err := h2.Write(chunk); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	chunked := h2.Sum()
+
+	if !whole.Equal(&chunked) {
+		t.Fatalf("writing in one call vs several produced different digests: %s vs %s", whole.String(), chunked.String())
+	}
+}
+
+func TestDomainSeparation(t *testing.T) {
+	var e fr.Element
+	e.SetUint64(42)
+
+	h1, err := New(1, 1, []byte("a"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	h1.WriteElement(e)
+	sum1 := h1.Sum()
+
+	h2, err := New(1, 1, []byte("b"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	h2.WriteElement(e)
+	sum2 := h2.Sum()
+
+	if sum1.Equal(&sum2) {
+		t.Fatal("different domain tags produced the same digest")
+	}
+}
+
+func TestWriteAfterSumErrors(t *testing.T) {
+	h, err := New(1, 1, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	h.Sum()
+
+	if _, err := h.Write([]byte{1}); err == nil {
+		t.Fatal("Write after Sum: expected an error, got nil")
+	}
+}
+
+func TestWriteElementAfterSumErrors(t *testing.T) {
+	h, err := New(1, 1, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	h.Sum()
+
+	var e fr.Element
+	e.SetUint64(1)
+	if err := h.WriteElement(e); err == nil {
+		t.Fatal("WriteElement after Sum: expected an error, got nil")
+	}
+}
+
+func TestResetAllowsReuse(t *testing.T) {
+	var a, b fr.Element
+	a.SetUint64(1)
+	b.SetUint64(2)
+
+	h, err := New(1, 1, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	h.WriteElement(a)
+	first := h.Sum()
+
+	h.Reset()
+	h.WriteElement(b)
+	second := h.Sum()
+
+	if first.Equal(&second) {
+		t.Fatal("Reset did not clear state between hashes")
+	}
+
+	h.Reset()
+	if err := h.WriteElement(a); err != nil {
+		t.Fatalf("WriteElement after Reset: unexpected error: %v", err)
+	}
+}
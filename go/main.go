@@ -1,31 +1,69 @@
+// Command poseidon2 is a small CLI around the Poseidon2 permutation,
+// compression, sponge hash, and Merkle tree primitives implemented in this
+// module.
 package main
 
 import (
-	"os"
 	"fmt"
-
-	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
-	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/poseidon2"
+	"os"
 )
 
 func main() {
-	hasher := poseidon2.NewPermutation(2, 8, 56)
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
 
-	var left, right fr.Element
-	_, err := left.SetString(os.Args[1])
-	if err != nil {
-		panic(err)
+	cmd, args := os.Args[1], os.Args[2:]
+
+	var err error
+	switch cmd {
+	case "compress":
+		err = runCompress(args)
+	case "hash":
+		err = runHash(args)
+	case "permute":
+		err = runPermute(args)
+	case "merkle-root":
+		err = runMerkleRoot(args)
+	case "merkle-prove":
+		err = runMerkleProve(args)
+	case "merkle-verify":
+		err = runMerkleVerify(args)
+	case "evm-vectors":
+		err = runEvmVectors(args)
+	case "-h", "--help", "help":
+		usage()
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", cmd)
+		usage()
+		os.Exit(1)
 	}
-	_, err = right.SetString(os.Args[2])
+
 	if err != nil {
-		panic(err)
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
 	}
+}
+
+func usage() {
+	fmt.Fprint(os.Stderr, `usage: poseidon2 <command> [flags] [args]
 
-	state := []fr.Element{left, right}
-	hasher.Permutation(state)
+commands:
+  compress       2-to-1 Poseidon2 compression of two field elements
+  hash           sponge hash of one or more field elements
+  permute        raw Poseidon2 permutation of a state
+  merkle-root    compute the Merkle root of a list of leaves
+  merkle-prove   compute a Merkle proof for one leaf
+  merkle-verify  verify a Merkle proof
+  evm-vectors    write Foundry/Hardhat compress() test vectors from stdin pairs
 
-	var result fr.Element
-	result.Add(&state[1], &right) // feed-forward step
+inputs may be given as decimal ("123"), 0x-hex ("0x7b"), or "-" to read 32
+raw little-endian bytes from stdin. Use --format to select decimal, hex, or
+json output, and --batch (compress, hash) to stream newline-delimited
+records from stdin instead of taking positional arguments.
 
-	fmt.Printf("Compress Result: %s\n", result.String())
+Run 'poseidon2 <command> -h' for command-specific flags.
+`)
 }
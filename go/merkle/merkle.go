@@ -0,0 +1,151 @@
+// Package merkle implements a fixed-depth sparse Merkle tree whose nodes
+// are computed with the same Poseidon2 compression function used by the
+// top-level compress command: a permutation of {left, right} followed by
+// the feed-forward step state[1] + right. This keeps tree roots and proofs
+// bit-compatible with that function so they can be verified cheaply inside
+// a SNARK circuit that implements the same compression.
+package merkle
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/poseidon2"
+)
+
+// compress combines left and right exactly as the single-shot compression
+// function does: permute {left, right} and feed right forward into the
+// second limb of the resulting state.
+func compress(h *poseidon2.Permutation, left, right fr.Element) fr.Element {
+	state := []fr.Element{left, right}
+	h.Permutation(state)
+
+	var result fr.Element
+	result.Add(&state[1], &right)
+	return result
+}
+
+// Compress is the public 2-to-1 Poseidon2 compression primitive: it
+// permutes {left, right} and feeds right forward into the second limb of
+// the resulting state. It is the building block both this package and
+// other callers (CLI, server, EVM encoders) use to stay bit-compatible
+// with the tree's own node hashing.
+func Compress(left, right fr.Element) fr.Element {
+	h := poseidon2.NewPermutation(2, 8, 56)
+	return compress(h, left, right)
+}
+
+// Tree is a sparse Merkle tree of fixed depth over fr.Element leaves.
+// Subtrees that have never received a leaf are treated as filled with a
+// fixed zero leaf, so Root and Prove are well-defined before any Insert.
+type Tree struct {
+	depth  int
+	hasher *poseidon2.Permutation
+
+	// zero[i] is the root of an empty subtree of height i; zero[0] is the
+	// zero leaf itself.
+	zero []fr.Element
+
+	// nodes[i] holds the explicitly-set nodes at level i (0 = leaves),
+	// keyed by their index within that level.
+	nodes []map[uint64]fr.Element
+}
+
+// NewTree creates an empty sparse Merkle tree with the given depth, so it
+// can hold up to 2^depth leaves. depth must be non-negative.
+func NewTree(depth int) (*Tree, error) {
+	if depth < 0 {
+		return nil, fmt.Errorf("merkle: depth must be non-negative, got %d", depth)
+	}
+
+	h := poseidon2.NewPermutation(2, 8, 56)
+
+	zero := make([]fr.Element, depth+1)
+	for i := 1; i <= depth; i++ {
+		zero[i] = compress(h, zero[i-1], zero[i-1])
+	}
+
+	nodes := make([]map[uint64]fr.Element, depth+1)
+	for i := range nodes {
+		nodes[i] = make(map[uint64]fr.Element)
+	}
+
+	return &Tree{depth: depth, hasher: h, zero: zero, nodes: nodes}, nil
+}
+
+// nodeAt returns the node at (level, idx), falling back to the precomputed
+// empty-subtree value when it has not been set.
+func (t *Tree) nodeAt(level int, idx uint64) fr.Element {
+	if v, ok := t.nodes[level][idx]; ok {
+		return v
+	}
+	return t.zero[level]
+}
+
+// Insert sets the leaf at index and recomputes every ancestor up to the
+// root.
+func (t *Tree) Insert(index uint64, leaf fr.Element) {
+	t.nodes[0][index] = leaf
+
+	idx := index
+	cur := leaf
+	for level := 0; level < t.depth; level++ {
+		var left, right fr.Element
+		if idx%2 == 0 {
+			left, right = cur, t.nodeAt(level, idx+1)
+		} else {
+			left, right = t.nodeAt(level, idx-1), cur
+		}
+		cur = compress(t.hasher, left, right)
+		idx /= 2
+		t.nodes[level+1][idx] = cur
+	}
+}
+
+// Root returns the current root of the tree.
+func (t *Tree) Root() fr.Element {
+	return t.nodeAt(t.depth, 0)
+}
+
+// Prove returns the sibling path for the leaf at index, along with, for
+// each level, whether that sibling is the left child (true) or the right
+// child (false).
+func (t *Tree) Prove(index uint64) (path []fr.Element, indices []bool, err error) {
+	if index >= uint64(1)<<uint(t.depth) {
+		return nil, nil, fmt.Errorf("merkle: index %d out of range for depth %d", index, t.depth)
+	}
+
+	path = make([]fr.Element, t.depth)
+	indices = make([]bool, t.depth)
+
+	idx := index
+	for level := 0; level < t.depth; level++ {
+		if idx%2 == 0 {
+			path[level] = t.nodeAt(level, idx+1)
+			indices[level] = false
+		} else {
+			path[level] = t.nodeAt(level, idx-1)
+			indices[level] = true
+		}
+		idx /= 2
+	}
+
+	return path, indices, nil
+}
+
+// Verify recomputes the root from leaf and its sibling path and reports
+// whether it matches root.
+func Verify(root, leaf fr.Element, path []fr.Element, indices []bool) bool {
+	h := poseidon2.NewPermutation(2, 8, 56)
+
+	cur := leaf
+	for i, sibling := range path {
+		if indices[i] {
+			cur = compress(h, sibling, cur)
+		} else {
+			cur = compress(h, cur, sibling)
+		}
+	}
+
+	return cur.Equal(&root)
+}
@@ -0,0 +1,113 @@
+package merkle
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/poseidon2"
+)
+
+// BuildTreeParallel builds a dense Merkle tree over leaves, sharding each
+// level's adjacent-pair compressions across workers goroutines. leaves is
+// padded up to the next power of two with the zero leaf so every level
+// halves evenly; the result is bit-for-bit identical to inserting the same
+// leaves one at a time via Tree.Insert. layers[0] is the padded leaf level
+// and layers[len(layers)-1] is [root].
+func BuildTreeParallel(leaves []fr.Element, workers int) (root fr.Element, layers [][]fr.Element, err error) {
+	if len(leaves) == 0 {
+		return fr.Element{}, nil, errors.New("merkle: BuildTreeParallel requires at least one leaf")
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	n := nextPowerOfTwo(len(leaves))
+	padded := make([]fr.Element, n)
+	copy(padded, leaves)
+
+	depth := 0
+	for (1 << uint(depth)) < n {
+		depth++
+	}
+
+	layers = make([][]fr.Element, depth+1)
+	layers[0] = padded
+
+	current := padded
+	for level := 0; level < depth; level++ {
+		next := make([]fr.Element, len(current)/2)
+		compressLevelParallel(current, next, workers)
+		layers[level+1] = next
+		current = next
+	}
+
+	return current[0], layers, nil
+}
+
+// compressLevelParallel fills next[i] = compress(current[2i], current[2i+1])
+// for every i, splitting the work into contiguous chunks across up to
+// workers goroutines. Each goroutine owns a private permutation instance,
+// so results never depend on scheduling order.
+func compressLevelParallel(current, next []fr.Element, workers int) {
+	pairs := len(next)
+	if workers > pairs {
+		workers = pairs
+	}
+	if workers <= 1 {
+		h := poseidon2.NewPermutation(2, 8, 56)
+		for i := range next {
+			next[i] = compress(h, current[2*i], current[2*i+1])
+		}
+		return
+	}
+
+	chunk := (pairs + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < pairs; start += chunk {
+		end := start + chunk
+		if end > pairs {
+			end = pairs
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			h := poseidon2.NewPermutation(2, 8, 56)
+			for i := start; i < end; i++ {
+				next[i] = compress(h, current[2*i], current[2*i+1])
+			}
+		}(start, end)
+	}
+	wg.Wait()
+}
+
+// PermutationBatch runs the Poseidon2 permutation over every state
+// in-place, in a single tight loop over one reused permutation instance.
+// All states must share the same width; processing them back-to-back
+// (rather than through separate NewPermutation/Permutation call sites)
+// gives the Go compiler a straight-line loop it can vectorize, which
+// matters when precomputing millions of independent leaf or node hashes.
+func PermutationBatch(states [][]fr.Element) error {
+	if len(states) == 0 {
+		return nil
+	}
+
+	width := len(states[0])
+	h := poseidon2.NewPermutation(width, 8, 56)
+	for i, state := range states {
+		if len(state) != width {
+			return fmt.Errorf("merkle: state %d has width %d, want %d", i, len(state), width)
+		}
+		h.Permutation(state)
+	}
+	return nil
+}
+
+func nextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
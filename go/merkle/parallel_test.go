@@ -0,0 +1,110 @@
+package merkle
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/poseidon2"
+)
+
+// leavesOfLen returns n leaves, each leaf i set to the field element i+1 so
+// that every leaf is distinct and non-zero.
+func leavesOfLen(n int) []fr.Element {
+	leaves := make([]fr.Element, n)
+	for i := range leaves {
+		leaves[i].SetUint64(uint64(i + 1))
+	}
+	return leaves
+}
+
+// depthFor returns the depth of a dense tree holding n leaves, i.e. the
+// same padding BuildTreeParallel applies internally.
+func depthFor(n int) int {
+	size := nextPowerOfTwo(n)
+	depth := 0
+	for (1 << uint(depth)) < size {
+		depth++
+	}
+	return depth
+}
+
+func TestBuildTreeParallelMatchesSequential(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 5, 8, 17, 32} {
+		for _, workers := range []int{1, 2, 4, 8} {
+			t.Run(fmt.Sprintf("n=%d/workers=%d", n, workers), func(t *testing.T) {
+				leaves := leavesOfLen(n)
+
+				wantTree, err := NewTree(depthFor(n))
+				if err != nil {
+					t.Fatalf("NewTree: %v", err)
+				}
+				for i, leaf := range leaves {
+					wantTree.Insert(uint64(i), leaf)
+				}
+				want := wantTree.Root()
+
+				got, layers, err := BuildTreeParallel(leaves, workers)
+				if err != nil {
+					t.Fatalf("BuildTreeParallel: %v", err)
+				}
+				if !got.Equal(&want) {
+					t.Fatalf("root mismatch: got %s, want %s", got.String(), want.String())
+				}
+				if root := layers[len(layers)-1][0]; !root.Equal(&want) {
+					t.Fatalf("top layer mismatch: got %s, want %s", root.String(), want.String())
+				}
+			})
+		}
+	}
+}
+
+func TestBuildTreeParallelRejectsNoLeaves(t *testing.T) {
+	if _, _, err := BuildTreeParallel(nil, 4); err == nil {
+		t.Fatal("expected an error for zero leaves, got nil")
+	}
+}
+
+func TestPermutationBatchMatchesIndividualPermutation(t *testing.T) {
+	const width = 2
+	states := make([][]fr.Element, 5)
+	want := make([][]fr.Element, len(states))
+	for i := range states {
+		states[i] = []fr.Element{{}, {}}
+		states[i][0].SetUint64(uint64(i))
+		states[i][1].SetUint64(uint64(i * 2))
+
+		want[i] = make([]fr.Element, width)
+		copy(want[i], states[i])
+	}
+
+	for i := range want {
+		poseidon2.NewPermutation(width, 8, 56).Permutation(want[i])
+	}
+
+	if err := PermutationBatch(states); err != nil {
+		t.Fatalf("PermutationBatch: %v", err)
+	}
+
+	for i := range states {
+		for j := range states[i] {
+			if !states[i][j].Equal(&want[i][j]) {
+				t.Fatalf("state %d limb %d mismatch: got %s, want %s", i, j, states[i][j].String(), want[i][j].String())
+			}
+		}
+	}
+}
+
+func BenchmarkBuildTreeParallel(b *testing.B) {
+	leaves := leavesOfLen(1 << 14)
+	for _, workers := range []int{1, 2, 4, 8, 16} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := BuildTreeParallel(leaves, workers); err != nil {
+					b.Fatalf("BuildTreeParallel: %v", err)
+				}
+			}
+		})
+	}
+}
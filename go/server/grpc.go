@@ -0,0 +1,208 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+
+	"github.com/heliaxdev/poseidon2-evm-bls12-381/hash"
+)
+
+// jsonCodec lets Poseidon2ServiceServer exchange plain JSON-encoded Go
+// structs over gRPC's HTTP/2 framing. proto/poseidon2.proto is the source
+// of truth for the wire shape; this avoids requiring a protoc toolchain
+// just to build this module. Clients select it via the "json" grpc
+// content-subtype (e.g. grpc.CallContentSubtype("json")).
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                       { return "json" }
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// Poseidon2ServiceServer is the marker interface grpc.RegisterService
+// checks the registered implementation against; Server satisfies it
+// trivially since every RPC is dispatched through ServiceDesc below rather
+// than typed methods.
+type Poseidon2ServiceServer interface{}
+
+// ServiceDesc is the hand-written equivalent of what protoc-gen-go-grpc
+// would generate from proto/poseidon2.proto. Register it with:
+//
+//	grpc.NewServer().RegisterService(&server.ServiceDesc, server.NewServer())
+var ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "poseidon2.Poseidon2Service",
+	HandlerType: (*Poseidon2ServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Compress", Handler: compressUnary},
+		{MethodName: "Permute", Handler: permuteUnary},
+		{MethodName: "Hash", Handler: hashUnary},
+		{MethodName: "MerkleRoot", Handler: merkleRootUnary},
+		{MethodName: "MerkleProve", Handler: merkleProveUnary},
+		{MethodName: "MerkleVerify", Handler: merkleVerifyUnary},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "HashLeaves",
+			Handler:       hashLeavesStream,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "server/proto/poseidon2.proto",
+}
+
+func compressUnary(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	var req compressParams
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	s := srv.(*Server)
+	if interceptor == nil {
+		return s.compress(mustMarshal(req))
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/poseidon2.Poseidon2Service/Compress"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return s.compress(mustMarshal(req))
+	}
+	return interceptor(ctx, req, info, handler)
+}
+
+func permuteUnary(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	var req permuteParams
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	s := srv.(*Server)
+	if interceptor == nil {
+		return s.permute(mustMarshal(req))
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/poseidon2.Poseidon2Service/Permute"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return s.permute(mustMarshal(req))
+	}
+	return interceptor(ctx, req, info, handler)
+}
+
+func merkleRootUnary(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	var req merkleRootParams
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	s := srv.(*Server)
+	if interceptor == nil {
+		return s.merkleRoot(mustMarshal(req))
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/poseidon2.Poseidon2Service/MerkleRoot"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return s.merkleRoot(mustMarshal(req))
+	}
+	return interceptor(ctx, req, info, handler)
+}
+
+func hashUnary(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	var req hashParams
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	s := srv.(*Server)
+	if interceptor == nil {
+		return s.hash(mustMarshal(req))
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/poseidon2.Poseidon2Service/Hash"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return s.hash(mustMarshal(req))
+	}
+	return interceptor(ctx, req, info, handler)
+}
+
+func merkleProveUnary(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	var req merkleProveParams
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	s := srv.(*Server)
+	if interceptor == nil {
+		return s.merkleProve(mustMarshal(req))
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/poseidon2.Poseidon2Service/MerkleProve"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return s.merkleProve(mustMarshal(req))
+	}
+	return interceptor(ctx, req, info, handler)
+}
+
+func merkleVerifyUnary(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	var req merkleVerifyParams
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	s := srv.(*Server)
+	if interceptor == nil {
+		return s.merkleVerify(mustMarshal(req))
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/poseidon2.Poseidon2Service/MerkleVerify"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return s.merkleVerify(mustMarshal(req))
+	}
+	return interceptor(ctx, req, info, handler)
+}
+
+// hashLeavesStream implements the bidirectional HashLeaves RPC: every
+// received leaf preimage is sponge-hashed with a dedicated rate-1/
+// capacity-1 domain and streamed back immediately, so a client building a
+// million-leaf tree can pipeline hashing instead of issuing one unary call
+// per leaf.
+func hashLeavesStream(srv any, stream grpc.ServerStream) error {
+	for {
+		var req HashLeafRequest
+		if err := stream.RecvMsg(&req); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		leaf, err := hexToElement(req.Leaf)
+		if err != nil {
+			return fmt.Errorf("HashLeaves: %w", err)
+		}
+
+		h, err := hash.New(1, 1, []byte("merkle-leaf"))
+		if err != nil {
+			return fmt.Errorf("HashLeaves: %w", err)
+		}
+		if err := h.WriteElement(leaf); err != nil {
+			return fmt.Errorf("HashLeaves: %w", err)
+		}
+
+		if err := stream.SendMsg(&HashLeafResponse{Hash: elementToHex(h.Sum())}); err != nil {
+			return err
+		}
+	}
+}
+
+// HashLeafRequest and HashLeafResponse mirror proto/poseidon2.proto; the
+// json struct tags are what jsonCodec serializes over the wire.
+type HashLeafRequest struct {
+	Leaf string `json:"leaf"`
+}
+
+type HashLeafResponse struct {
+	Hash string `json:"hash"`
+}
+
+func mustMarshal(v any) json.RawMessage {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("server: marshaling decoded gRPC request: %v", err))
+	}
+	return b
+}
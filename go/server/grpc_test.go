@@ -0,0 +1,35 @@
+package server
+
+import "testing"
+
+func TestServiceDescExposesAllJSONRPCMethods(t *testing.T) {
+	want := map[string]bool{
+		"Compress":     false,
+		"Permute":      false,
+		"Hash":         false,
+		"MerkleRoot":   false,
+		"MerkleProve":  false,
+		"MerkleVerify": false,
+	}
+	for _, m := range ServiceDesc.Methods {
+		if _, ok := want[m.MethodName]; !ok {
+			t.Fatalf("unexpected gRPC method %q", m.MethodName)
+		}
+		want[m.MethodName] = true
+	}
+	for name, seen := range want {
+		if !seen {
+			t.Errorf("ServiceDesc is missing unary method %q", name)
+		}
+	}
+
+	foundHashLeaves := false
+	for _, s := range ServiceDesc.Streams {
+		if s.StreamName == "HashLeaves" {
+			foundHashLeaves = true
+		}
+	}
+	if !foundHashLeaves {
+		t.Error("ServiceDesc is missing the HashLeaves stream")
+	}
+}
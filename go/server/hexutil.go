@@ -0,0 +1,38 @@
+package server
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// hexToElement parses a go-ethereum hexutil-style 0x-prefixed hex string
+// (odd nibble counts allowed, as hexutil's big.Int encoding produces) into
+// a field element.
+func hexToElement(s string) (fr.Element, error) {
+	var e fr.Element
+
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		return e, fmt.Errorf("hexutil: value %q missing 0x prefix", s)
+	}
+
+	h := s[2:]
+	if len(h)%2 != 0 {
+		h = "0" + h
+	}
+	b, err := hex.DecodeString(h)
+	if err != nil {
+		return e, fmt.Errorf("hexutil: invalid hex value %q: %w", s, err)
+	}
+
+	e.SetBytes(b)
+	return e, nil
+}
+
+// elementToHex renders e as a 0x-prefixed, big-endian hex string.
+func elementToHex(e fr.Element) string {
+	b := e.Bytes()
+	return "0x" + hex.EncodeToString(b[:])
+}
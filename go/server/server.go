@@ -0,0 +1,295 @@
+// Package server exposes the Poseidon2 permutation, 2-to-1 compression,
+// sponge hash, and Merkle tree operations from this module over the
+// network, so non-Go clients (zk circuits, Solidity test harnesses, ...)
+// can use this implementation as their oracle instead of re-linking
+// gnark-crypto. Two transports are offered: JSON-RPC 2.0 over HTTP (see
+// Server.ServeHTTP) and gRPC (see grpc.go). Both take field elements as
+// 0x-prefixed hex strings, matching the go-ethereum hexutil convention.
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/poseidon2"
+
+	"github.com/heliaxdev/poseidon2-evm-bls12-381/hash"
+	"github.com/heliaxdev/poseidon2-evm-bls12-381/merkle"
+)
+
+// Server implements the JSON-RPC 2.0 methods backing both transports.
+type Server struct{}
+
+// NewServer creates a Server. It is stateless: every call builds its own
+// permutation/tree from the request, so a single Server can be shared
+// across concurrent requests.
+func NewServer() *Server {
+	return &Server{}
+}
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	ID      json.RawMessage `json:"id"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+const (
+	rpcErrParse    = -32700
+	rpcErrInvalid  = -32600
+	rpcErrNotFound = -32601
+	rpcErrParams   = -32602
+	rpcErrInternal = -32603
+)
+
+// ServeHTTP implements the JSON-RPC 2.0 transport: POST a single request
+// object (batches are not supported) and receive a single response object.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req rpcRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeRPC(w, rpcResponse{JSONRPC: "2.0", Error: &rpcError{Code: rpcErrParse, Message: err.Error()}})
+		return
+	}
+
+	handler, ok := s.methods()[req.Method]
+	if !ok {
+		writeRPC(w, rpcResponse{JSONRPC: "2.0", ID: req.ID, Error: &rpcError{Code: rpcErrNotFound, Message: fmt.Sprintf("method %q not found", req.Method)}})
+		return
+	}
+
+	result, err := handler(req.Params)
+	if err != nil {
+		writeRPC(w, rpcResponse{JSONRPC: "2.0", ID: req.ID, Error: &rpcError{Code: rpcErrParams, Message: err.Error()}})
+		return
+	}
+
+	writeRPC(w, rpcResponse{JSONRPC: "2.0", ID: req.ID, Result: result})
+}
+
+func writeRPC(w http.ResponseWriter, resp rpcResponse) {
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (s *Server) methods() map[string]func(json.RawMessage) (any, error) {
+	return map[string]func(json.RawMessage) (any, error){
+		"permute":       s.permute,
+		"compress":      s.compress,
+		"hash":          s.hash,
+		"merkle_root":   s.merkleRoot,
+		"merkle_prove":  s.merkleProve,
+		"merkle_verify": s.merkleVerify,
+	}
+}
+
+type permuteParams struct {
+	Width    int      `json:"width"`
+	Elements []string `json:"elements"`
+}
+
+func (s *Server) permute(raw json.RawMessage) (any, error) {
+	var p permuteParams
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, err
+	}
+	if p.Width != 2 && p.Width != 3 {
+		return nil, fmt.Errorf("unsupported width %d; only 2 or 3 are supported", p.Width)
+	}
+	if len(p.Elements) != p.Width {
+		return nil, fmt.Errorf("expected %d elements for width %d, got %d", p.Width, p.Width, len(p.Elements))
+	}
+
+	state := make([]fr.Element, p.Width)
+	for i, s := range p.Elements {
+		e, err := hexToElement(s)
+		if err != nil {
+			return nil, err
+		}
+		state[i] = e
+	}
+
+	poseidon2.NewPermutation(p.Width, 8, 56).Permutation(state)
+
+	out := make([]string, p.Width)
+	for i, e := range state {
+		out[i] = elementToHex(e)
+	}
+	return map[string][]string{"state": out}, nil
+}
+
+type compressParams struct {
+	Left  string `json:"left"`
+	Right string `json:"right"`
+}
+
+func (s *Server) compress(raw json.RawMessage) (any, error) {
+	var p compressParams
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, err
+	}
+	left, err := hexToElement(p.Left)
+	if err != nil {
+		return nil, err
+	}
+	right, err := hexToElement(p.Right)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{"result": elementToHex(merkle.Compress(left, right))}, nil
+}
+
+type hashParams struct {
+	Elements []string `json:"elements"`
+	Rate     int      `json:"rate"`
+	Capacity int      `json:"capacity"`
+	Domain   string   `json:"domain"`
+}
+
+func (s *Server) hash(raw json.RawMessage) (any, error) {
+	var p hashParams
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, err
+	}
+
+	var domain []byte
+	if p.Domain != "" {
+		d, err := hexToElement(p.Domain)
+		if err != nil {
+			return nil, err
+		}
+		b := d.Bytes()
+		domain = b[:]
+	}
+
+	h, err := hash.New(p.Rate, p.Capacity, domain)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range p.Elements {
+		e, err := hexToElement(s)
+		if err != nil {
+			return nil, err
+		}
+		if err := h.WriteElement(e); err != nil {
+			return nil, err
+		}
+	}
+
+	return map[string]string{"result": elementToHex(h.Sum())}, nil
+}
+
+type merkleRootParams struct {
+	Depth  int      `json:"depth"`
+	Leaves []string `json:"leaves"`
+}
+
+func (s *Server) merkleRoot(raw json.RawMessage) (any, error) {
+	var p merkleRootParams
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, err
+	}
+	t, err := buildTree(p.Depth, p.Leaves)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"root": elementToHex(t.Root())}, nil
+}
+
+type merkleProveParams struct {
+	Depth  int      `json:"depth"`
+	Leaves []string `json:"leaves"`
+	Index  uint64   `json:"index"`
+}
+
+func (s *Server) merkleProve(raw json.RawMessage) (any, error) {
+	var p merkleProveParams
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, err
+	}
+	t, err := buildTree(p.Depth, p.Leaves)
+	if err != nil {
+		return nil, err
+	}
+
+	path, indices, err := t.Prove(p.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	pathHex := make([]string, len(path))
+	for i, e := range path {
+		pathHex[i] = elementToHex(e)
+	}
+	return map[string]any{"path": pathHex, "indices": indices}, nil
+}
+
+type merkleVerifyParams struct {
+	Root    string   `json:"root"`
+	Leaf    string   `json:"leaf"`
+	Path    []string `json:"path"`
+	Indices []bool   `json:"indices"`
+}
+
+func (s *Server) merkleVerify(raw json.RawMessage) (any, error) {
+	var p merkleVerifyParams
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, err
+	}
+	root, err := hexToElement(p.Root)
+	if err != nil {
+		return nil, err
+	}
+	leaf, err := hexToElement(p.Leaf)
+	if err != nil {
+		return nil, err
+	}
+	if len(p.Path) != len(p.Indices) {
+		return nil, fmt.Errorf("path has %d elements but indices has %d", len(p.Path), len(p.Indices))
+	}
+	path := make([]fr.Element, len(p.Path))
+	for i, s := range p.Path {
+		e, err := hexToElement(s)
+		if err != nil {
+			return nil, err
+		}
+		path[i] = e
+	}
+
+	return map[string]bool{"valid": merkle.Verify(root, leaf, path, p.Indices)}, nil
+}
+
+// buildTree inserts leaves 0..len(leaves)-1 into a fresh tree of the given
+// depth, parsing each leaf as hexutil-style hex.
+func buildTree(depth int, leafHexes []string) (*merkle.Tree, error) {
+	t, err := merkle.NewTree(depth)
+	if err != nil {
+		return nil, err
+	}
+	if len(leafHexes) > 1<<uint(depth) {
+		return nil, fmt.Errorf("%d leaves do not fit in depth %d (max %d)", len(leafHexes), depth, 1<<uint(depth))
+	}
+	for i, s := range leafHexes {
+		e, err := hexToElement(s)
+		if err != nil {
+			return nil, err
+		}
+		t.Insert(uint64(i), e)
+	}
+	return t, nil
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func call(t *testing.T, s *Server, method string, params any) (any, error) {
+	t.Helper()
+	raw, err := json.Marshal(params)
+	if err != nil {
+		t.Fatalf("marshal params: %v", err)
+	}
+	return s.methods()[method](raw)
+}
+
+func TestPermuteRejectsUnsupportedWidth(t *testing.T) {
+	s := NewServer()
+	_, err := call(t, s, "permute", permuteParams{Width: 4, Elements: []string{"0x1", "0x2", "0x3", "0x4"}})
+	if err == nil {
+		t.Fatal("expected an error for width 4, got nil")
+	}
+}
+
+func TestPermuteAcceptsSupportedWidth(t *testing.T) {
+	s := NewServer()
+	out, err := call(t, s, "permute", permuteParams{Width: 2, Elements: []string{"0x1", "0x2"}})
+	if err != nil {
+		t.Fatalf("permute: %v", err)
+	}
+	state, ok := out.(map[string][]string)["state"]
+	if !ok || len(state) != 2 {
+		t.Fatalf("permute result = %v, want a 2-element state", out)
+	}
+}
+
+func TestHashRejectsUnsupportedWidth(t *testing.T) {
+	s := NewServer()
+	_, err := call(t, s, "hash", hashParams{Elements: []string{"0x1"}, Rate: 2, Capacity: 2})
+	if err == nil {
+		t.Fatal("expected an error for rate+capacity=4, got nil")
+	}
+}
+
+func TestHashAcceptsSupportedWidth(t *testing.T) {
+	s := NewServer()
+	out, err := call(t, s, "hash", hashParams{Elements: []string{"0x1"}, Rate: 1, Capacity: 1})
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+	if _, ok := out.(map[string]string)["result"]; !ok {
+		t.Fatalf("hash result = %v, want a result field", out)
+	}
+}
+
+func TestMerkleRootProveVerifyRoundTrip(t *testing.T) {
+	s := NewServer()
+	root, err := call(t, s, "merkle_root", merkleRootParams{Depth: 2, Leaves: []string{"0x1", "0x2"}})
+	if err != nil {
+		t.Fatalf("merkle_root: %v", err)
+	}
+	rootHex := root.(map[string]string)["root"]
+
+	proved, err := call(t, s, "merkle_prove", merkleProveParams{Depth: 2, Leaves: []string{"0x1", "0x2"}, Index: 0})
+	if err != nil {
+		t.Fatalf("merkle_prove: %v", err)
+	}
+	proof := proved.(map[string]any)
+
+	verified, err := call(t, s, "merkle_verify", merkleVerifyParams{
+		Root:    rootHex,
+		Leaf:    "0x1",
+		Path:    proof["path"].([]string),
+		Indices: proof["indices"].([]bool),
+	})
+	if err != nil {
+		t.Fatalf("merkle_verify: %v", err)
+	}
+	if !verified.(map[string]bool)["valid"] {
+		t.Fatal("merkle_verify: expected a valid proof")
+	}
+}
+
+func TestMethodsHasNoHandlerForUnknownMethod(t *testing.T) {
+	s := NewServer()
+	if _, ok := s.methods()["bogus"]; ok {
+		t.Fatal("expected no handler for an unknown method")
+	}
+}